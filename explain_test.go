@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHandleGetReceiptPointsExplain covers the ?explain=1 query param added
+// alongside the rule engine: the breakdown should sum to the total and only
+// appear when explicitly requested.
+func TestHandleGetReceiptPointsExplain(t *testing.T) {
+	rh := NewReceiptHandler()
+
+	router := chi.NewRouter()
+	router.Post("/receipts/process", rh.handleProcessReceipts)
+	router.Get("/receipts/{id}/points", rh.handleGetReceiptPoints)
+
+	body := `{"retailer":"Target","purchaseDate":"2022-01-01","purchaseTime":"13:01","total":"35.35","items":[{"shortDescription":"Emils Cheese Pizza","price":"12.25"}]}`
+	postReq := httptest.NewRequest(http.MethodPost, "/receipts/process", strings.NewReader(body))
+	postRec := httptest.NewRecorder()
+	router.ServeHTTP(postRec, postReq)
+	assert.Equal(t, http.StatusOK, postRec.Code)
+
+	var postResp struct {
+		Id string `json:"id"`
+	}
+	assert.NoError(t, json.Unmarshal(postRec.Body.Bytes(), &postResp))
+
+	explainReq := httptest.NewRequest(http.MethodGet, "/receipts/"+postResp.Id+"/points?explain=1", nil)
+	explainRec := httptest.NewRecorder()
+	router.ServeHTTP(explainRec, explainReq)
+	assert.Equal(t, http.StatusOK, explainRec.Code)
+
+	var explainResp struct {
+		Points    int            `json:"points"`
+		Breakdown map[string]int `json:"breakdown"`
+	}
+	assert.NoError(t, json.Unmarshal(explainRec.Body.Bytes(), &explainResp))
+	assert.NotEmpty(t, explainResp.Breakdown)
+
+	sum := 0
+	for _, pts := range explainResp.Breakdown {
+		sum += pts
+	}
+	assert.Equal(t, explainResp.Points, sum)
+
+	plainReq := httptest.NewRequest(http.MethodGet, "/receipts/"+postResp.Id+"/points", nil)
+	plainRec := httptest.NewRecorder()
+	router.ServeHTTP(plainRec, plainReq)
+	assert.Equal(t, http.StatusOK, plainRec.Code)
+	assert.NotContains(t, plainRec.Body.String(), "breakdown")
+}
@@ -1,23 +1,101 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 )
 
 const PORT = 8085
 
 func main() {
-	rh := NewReceiptHandler()
+	rulesConfigPath := flag.String("rules-config", "", "path to a YAML or JSON file enabling/disabling rules and tuning their parameters")
+	rulesDir := flag.String("rules-dir", "", "directory of .so plugins exporting additional Rules")
+	storageKind := flag.String("storage", "memory", `storage backend to use: "memory", "bolt", or "sqlite"`)
+	storagePath := flag.String("storage-path", "receipts.db", "file path for the bolt/sqlite storage backend")
+	requestTimeout := flag.Duration("request-timeout", 5*time.Second, "default per-request timeout")
+	flag.Parse()
+
+	var cfg *RuleConfig
+	if *rulesConfigPath != "" {
+		var err error
+		cfg, err = LoadRuleConfig(*rulesConfigPath)
+		if err != nil {
+			log.Fatalf("loading rules config: %v", err)
+		}
+	}
+
+	rules := NewDefaultRuleRegistry(cfg)
+	if *rulesDir != "" {
+		if err := LoadPlugins(*rulesDir, rules); err != nil {
+			log.Fatalf("loading rule plugins: %v", err)
+		}
+	}
+
+	storage, err := newStorage(*storageKind, *storagePath)
+	if err != nil {
+		log.Fatalf("initializing storage: %v", err)
+	}
+
+	rh := NewReceiptHandlerWith(storage, rules)
 
 	r := chi.NewRouter()
+	r.Use(middleware.Timeout(*requestTimeout))
 
 	r.Post("/receipts/process", rh.handleProcessReceipts)
 	r.Get("/receipts/{id}/points", rh.handleGetReceiptPoints)
 
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", PORT),
+		Handler:           r,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      *requestTimeout + 5*time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+		<-sigCh
+
+		fmt.Println("shutting down, waiting for in-flight requests to complete")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		shutdownErr <- srv.Shutdown(ctx)
+	}()
+
 	fmt.Printf("Starting server on PORT: %d\n", PORT)
-	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", PORT), r))
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+
+	if err := <-shutdownErr; err != nil {
+		log.Fatal(err)
+	}
+}
+
+// newStorage builds the Storage backend selected by --storage. path is the
+// database file used by the bolt and sqlite backends; it is ignored for
+// memory.
+func newStorage(kind, path string) (Storage, error) {
+	switch kind {
+	case "memory":
+		return NewMemoryStorage(), nil
+	case "bolt":
+		return NewBoltStorage(path)
+	case "sqlite":
+		return NewSQLiteStorage(path)
+	default:
+		return nil, fmt.Errorf(`unknown storage backend %q, want "memory", "bolt", or "sqlite"`, kind)
+	}
 }
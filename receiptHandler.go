@@ -3,12 +3,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"math"
 	"net/http"
-	"strconv"
-	"strings"
-	"sync"
-	"unicode"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -39,28 +34,50 @@ type Item struct {
 	Price            string `json:"price"`
 }
 
-// StorageReceipt is a representation of what a receipt is in storage
+// StorageReceipt is a representation of what a receipt is in storage. Points
+// and Breakdown cache the last computed score for this receipt so
+// handleGetReceiptPoints doesn't recompute it on every request; RulesVersion
+// records which RuleRegistry.Version() produced that cache so it can be
+// detected as stale and recomputed after a rule config change.
 type StorageReceipt struct {
 	PostReceiptJSON
-	Id uuid.UUID `json:"id"`
+	Id           uuid.UUID      `json:"id"`
+	Points       *int           `json:"-"`
+	Breakdown    map[string]int `json:"-"`
+	RulesVersion string         `json:"-"`
 }
 
 // ReceiptHandler is the structure being used to contain all the handlers for
-// handling receipts, as well as maintaining an in-memory storage of the
-// receipts, as part of the prompt
+// handling receipts, backed by a Storage implementation for persistence.
 type ReceiptHandler struct {
-	mu       sync.Mutex
-	Receipts []StorageReceipt
+	Storage Storage
+	Rules   *RuleRegistry
 }
 
+// NewReceiptHandler returns a ReceiptHandler using in-memory storage and the
+// default built-in rule set with no config overrides.
 func NewReceiptHandler() *ReceiptHandler {
-	return &ReceiptHandler{}
+	return &ReceiptHandler{
+		Storage: NewMemoryStorage(),
+		Rules:   NewDefaultRuleRegistry(nil),
+	}
+}
+
+// NewReceiptHandlerWith returns a ReceiptHandler using the given Storage and
+// RuleRegistry, e.g. a durable backend picked via --storage and a
+// RuleRegistry built from a RuleConfig and/or loaded plugins.
+func NewReceiptHandlerWith(storage Storage, rules *RuleRegistry) *ReceiptHandler {
+	return &ReceiptHandler{
+		Storage: storage,
+		Rules:   rules,
+	}
 }
 
 // handleProcessReceipts handles a POST request and stores the receipt in
 // storage
 func (rh *ReceiptHandler) handleProcessReceipts(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
+	ctx := r.Context()
 
 	var postReceipt PostReceiptJSON
 	decoder := json.NewDecoder(r.Body)
@@ -85,9 +102,11 @@ func (rh *ReceiptHandler) handleProcessReceipts(w http.ResponseWriter, r *http.R
 		PostReceiptJSON: postReceipt,
 	}
 
-	rh.mu.Lock()
-	defer rh.mu.Unlock()
-	rh.Receipts = append(rh.Receipts, storedData)
+	if err := rh.Storage.Put(ctx, storedData); err != nil {
+		fmt.Printf("storing receipt: %+v\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
 
 	// Note: These anonymous structs are used just for this prompt for
 	// simplicity
@@ -110,144 +129,91 @@ func (rh *ReceiptHandler) handleProcessReceipts(w http.ResponseWriter, r *http.R
 }
 
 // handleGetReceiptPoints is a handler that returns the points value for a
-// given receipt UUID
+// given receipt UUID. Passing ?explain=1 additionally returns a per-rule
+// breakdown of how those points were earned, which is useful for debugging
+// rule config changes without recompiling the server.
 func (rh *ReceiptHandler) handleGetReceiptPoints(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	idFromURL := chi.URLParam(r, "id")
+	explain := r.URL.Query().Get("explain") == "1"
 
-	rh.mu.Lock()
-	defer rh.mu.Unlock()
-	for _, receipt := range rh.Receipts {
-		if idFromURL == receipt.Id.String() {
-			points, err := findPoints(receipt)
-			if err != nil {
-				fmt.Println("error with calculating points: ", err)
-			}
-			// Note: These anonymous structs are used just for this prompt for
-			// simplicity
-			responsePoints := struct {
-				Points int `json:"points"`
-			}{
-				Points: points,
-			}
-
-			b, err := json.Marshal(responsePoints)
-			if err != nil {
-				fmt.Printf("marshaling json: %+v\n", err)
-				return
-			}
-
-			if _, err = w.Write(b); err != nil {
-				fmt.Printf("writing to response: %+v\n", err)
-				return
-			}
-			return
+	id, err := uuid.Parse(idFromURL)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		if _, err := w.Write([]byte("Id not found")); err != nil {
+			fmt.Printf("writing to response: %+v\n", err)
 		}
-	}
-
-	w.WriteHeader(http.StatusNotFound)
-	if _, err := w.Write([]byte("Id not found")); err != nil {
-		fmt.Printf("writing to response: %+v\n", err)
 		return
 	}
-}
 
-// findPoints is a helper function to count up how many points a receipt is
-// worth, following these given rules:
-// 1. One point for every alphanumeric character in the retailer name.
-//
-// 2. 50 points if the total is a round dollar amount with no cents.
-//
-// 3. 25 points if the total is a multiple of 0.25.
-//
-// 4. 5 points for every two items on the receipt.
-//
-// 5. If the trimmed length of the item description is a multiple of 3, multiply
-// the price by 0.2 and round up to the nearest integer. The result is the
-// number of points earned.
-//
-// 6. 6 points if the day in the purchase date is odd.
-//
-// 7. 10 points if the time of purchase is after 2:00pm and before 4:00pm
-//
-// Note: depending on feedback, each of these rules can be turned into a small,
-// separate function
-func findPoints(re StorageReceipt) (int, error) {
-	total := 0
-
-	// #1
-	count := 0
-	for _, c := range re.Retailer {
-		if unicode.IsLetter(c) || unicode.IsDigit(c) {
-			count++
-		}
-	}
-	total += count
-
-	totalPrice, err := strconv.ParseFloat(re.Total, 64)
+	receipt, ok, err := rh.Storage.Get(ctx, id)
 	if err != nil {
-		return 0, err
+		fmt.Printf("reading receipt: %+v\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
 	}
-
-	// #2
-	if totalPrice == math.Floor(totalPrice) {
-		total += 50
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		if _, err := w.Write([]byte("Id not found")); err != nil {
+			fmt.Printf("writing to response: %+v\n", err)
+		}
+		return
 	}
 
-	// #3
-	if math.Mod(totalPrice, 0.25) == 0 {
-		total += 25
+	if err := ctx.Err(); err != nil {
+		w.WriteHeader(http.StatusRequestTimeout)
+		return
 	}
 
-	// #4
-	numItems := len(re.Items)
-	m := math.Floor(float64(numItems) / 2)
-	total += int(m * 5)
-
-	// #5
-	for _, item := range re.Items {
-		trimmedDesc := strings.TrimSpace(item.ShortDescription)
-		if len(trimmedDesc)%3 == 0 {
-			itemPrice, err := strconv.ParseFloat(item.Price, 64)
-			if err != nil {
-				return 0, err
+	rulesVersion := rh.Rules.Version()
+	points, breakdown := 0, map[string]int(nil)
+	if receipt.Points != nil && receipt.RulesVersion == rulesVersion {
+		points, breakdown = *receipt.Points, receipt.Breakdown
+	} else {
+		points, breakdown, err = rh.Rules.Score(receipt)
+		if err != nil {
+			fmt.Println("error with calculating points: ", err)
+		} else {
+			receipt.Points = &points
+			receipt.Breakdown = breakdown
+			receipt.RulesVersion = rulesVersion
+			if err := rh.Storage.Put(ctx, receipt); err != nil {
+				fmt.Printf("caching computed points: %+v\n", err)
 			}
-
-			earnedPts := math.Ceil(itemPrice * 0.2)
-			total += int(earnedPts)
 		}
 	}
 
-	// #6
-	splitStrs := strings.Split(re.PurchaseDate, "-")
-	if len(splitStrs) != 3 {
-		return 0, fmt.Errorf("invalid date. Must follow the YYYY-MM-DD scheme")
-	}
-	date := splitStrs[2]
-	dateNum, err := strconv.Atoi(date)
-	if err != nil {
-		return 0, err
-	}
-
-	if dateNum%2 == 1 {
-		total += 6
-	}
-
-	// #7
-	timeSplit := strings.Split(re.PurchaseTime, ":")
-	hrs, err := strconv.Atoi(timeSplit[0])
-	if err != nil {
-		return 0, err
+	// Note: These anonymous structs are used just for this prompt for
+	// simplicity
+	var b []byte
+	if explain {
+		response := struct {
+			Points    int            `json:"points"`
+			Breakdown map[string]int `json:"breakdown"`
+		}{
+			Points:    points,
+			Breakdown: breakdown,
+		}
+		b, err = json.Marshal(response)
+	} else {
+		response := struct {
+			Points int `json:"points"`
+		}{
+			Points: points,
+		}
+		b, err = json.Marshal(response)
 	}
-	mins, err := strconv.Atoi(timeSplit[1])
 	if err != nil {
-		return 0, err
+		fmt.Printf("marshaling json: %+v\n", err)
+		return
 	}
 
-	if hrs == 14 && mins == 0 {
-		fmt.Println("do nothing, because it's exactly 2pm")
-	} else if hrs >= 14 && hrs < 16 {
-		total += 10
+	if _, err = w.Write(b); err != nil {
+		fmt.Printf("writing to response: %+v\n", err)
+		return
 	}
-
-	return total, nil
 }
+
+// Scoring itself lives in the Rule implementations in rules_builtin.go, run
+// via ReceiptHandler.Rules. See rule.go, rules_builtin.go, and
+// rule_config.go for the rule engine.
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RuleConfig describes operator-controlled overrides for the built-in rule
+// set: which rules are enabled and what their scoring parameters should be.
+// It is read from a YAML or JSON file so weights like the alphanumeric
+// multiplier, the 14:00-16:00 afternoon window, or the 0.25-multiple bonus
+// can be tuned without recompiling the server.
+type RuleConfig struct {
+	Rules map[string]RuleParams `json:"rules" yaml:"rules"`
+}
+
+// RuleParams holds the overridable fields for a single rule. A nil field
+// means "leave the built-in default as-is". Not every rule uses every
+// field; unused ones are ignored by that rule's apply step.
+type RuleParams struct {
+	Enabled       *bool    `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Multiplier    *float64 `json:"multiplier,omitempty" yaml:"multiplier,omitempty"`
+	Bonus         *int     `json:"bonus,omitempty" yaml:"bonus,omitempty"`
+	PointsPerPair *int     `json:"pointsPerPair,omitempty" yaml:"pointsPerPair,omitempty"`
+	StartHour     *int     `json:"startHour,omitempty" yaml:"startHour,omitempty"`
+	EndHour       *int     `json:"endHour,omitempty" yaml:"endHour,omitempty"`
+}
+
+// LoadRuleConfig reads a RuleConfig from a YAML or JSON file, chosen by the
+// path's extension (.yaml/.yml or .json).
+func LoadRuleConfig(path string) (*RuleConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading rule config %q: %w", path, err)
+	}
+
+	var cfg RuleConfig
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(b, &cfg); err != nil {
+			return nil, fmt.Errorf("parsing rule config %q: %w", path, err)
+		}
+	case ".json":
+		if err := json.Unmarshal(b, &cfg); err != nil {
+			return nil, fmt.Errorf("parsing rule config %q: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported rule config extension %q, want .yaml, .yml, or .json", ext)
+	}
+
+	return &cfg, nil
+}
+
+// isDisabled reports whether the named rule was explicitly turned off in the
+// config. A nil RuleConfig disables nothing.
+func (c *RuleConfig) isDisabled(name string) bool {
+	if c == nil {
+		return false
+	}
+	p, ok := c.Rules[name]
+	return ok && p.Enabled != nil && !*p.Enabled
+}
+
+// apply returns r with any matching config overrides applied. Rule types
+// that don't recognize a given param simply ignore it.
+func (c *RuleConfig) apply(r Rule) Rule {
+	if c == nil {
+		return r
+	}
+	p, ok := c.Rules[r.Name()]
+	if !ok {
+		return r
+	}
+
+	switch rule := r.(type) {
+	case RetailerAlphanumericRule:
+		if p.Multiplier != nil {
+			rule.Multiplier = *p.Multiplier
+		}
+		return rule
+	case RoundDollarRule:
+		if p.Bonus != nil {
+			rule.Bonus = *p.Bonus
+		}
+		return rule
+	case QuarterMultipleRule:
+		if p.Bonus != nil {
+			rule.Bonus = *p.Bonus
+		}
+		return rule
+	case ItemPairRule:
+		if p.PointsPerPair != nil {
+			rule.PointsPerPair = *p.PointsPerPair
+		}
+		return rule
+	case ItemDescriptionRule:
+		if p.Multiplier != nil {
+			rule.Multiplier = *p.Multiplier
+		}
+		return rule
+	case OddPurchaseDayRule:
+		if p.Bonus != nil {
+			rule.Bonus = *p.Bonus
+		}
+		return rule
+	case AfternoonPurchaseRule:
+		if p.StartHour != nil {
+			rule.StartHour = *p.StartHour
+		}
+		if p.EndHour != nil {
+			rule.EndHour = *p.EndHour
+		}
+		if p.Bonus != nil {
+			rule.Bonus = *p.Bonus
+		}
+		return rule
+	default:
+		return r
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func boolPtr(b bool) *bool          { return &b }
+func intPtr(i int) *int             { return &i }
+func float64Ptr(f float64) *float64 { return &f }
+
+func TestRuleConfigIsDisabled(t *testing.T) {
+	cfg := &RuleConfig{
+		Rules: map[string]RuleParams{
+			"round-dollar-total": {Enabled: boolPtr(false)},
+			"item-pair":          {Enabled: boolPtr(true)},
+		},
+	}
+
+	assert.True(t, cfg.isDisabled("round-dollar-total"))
+	assert.False(t, cfg.isDisabled("item-pair"))
+	assert.False(t, cfg.isDisabled("quarter-multiple-total"))
+
+	var nilCfg *RuleConfig
+	assert.False(t, nilCfg.isDisabled("round-dollar-total"))
+}
+
+func TestNewDefaultRuleRegistryDisablesConfiguredRules(t *testing.T) {
+	cfg := &RuleConfig{
+		Rules: map[string]RuleParams{
+			"round-dollar-total": {Enabled: boolPtr(false)},
+		},
+	}
+
+	rules := NewDefaultRuleRegistry(cfg)
+	for _, r := range rules.Rules() {
+		assert.NotEqual(t, "round-dollar-total", r.Name())
+	}
+}
+
+func TestNewDefaultRuleRegistryAppliesParameterOverrides(t *testing.T) {
+	cfg := &RuleConfig{
+		Rules: map[string]RuleParams{
+			"retailer-alphanumeric": {Multiplier: float64Ptr(2)},
+			"round-dollar-total":    {Bonus: intPtr(5)},
+			"afternoon-purchase":    {StartHour: intPtr(9), EndHour: intPtr(11), Bonus: intPtr(1)},
+		},
+	}
+
+	rules := NewDefaultRuleRegistry(cfg)
+
+	re := StorageReceipt{
+		PostReceiptJSON: PostReceiptJSON{
+			Retailer:     "AB",
+			PurchaseDate: "2023-01-02",
+			PurchaseTime: "09:30",
+			Total:        "10.00",
+		},
+	}
+
+	_, breakdown, err := rules.Score(re)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 4, breakdown["retailer-alphanumeric"]) // 2 alnum chars * multiplier 2
+	assert.Equal(t, 5, breakdown["round-dollar-total"])    // overridden bonus
+	assert.Equal(t, 1, breakdown["afternoon-purchase"])    // 09:30 now falls in the 9-11 window
+}
+
+func TestLoadRuleConfigYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	contents := "rules:\n  round-dollar-total:\n    enabled: false\n"
+	assert.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+
+	cfg, err := LoadRuleConfig(path)
+	assert.NoError(t, err)
+	assert.True(t, cfg.isDisabled("round-dollar-total"))
+}
+
+func TestLoadRuleConfigJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	contents := `{"rules":{"item-pair":{"pointsPerPair":7}}}`
+	assert.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+
+	cfg, err := LoadRuleConfig(path)
+	assert.NoError(t, err)
+
+	rules := NewDefaultRuleRegistry(cfg)
+	re := StorageReceipt{
+		PostReceiptJSON: PostReceiptJSON{
+			Retailer:     "AB",
+			PurchaseDate: "2023-01-02",
+			PurchaseTime: "09:30",
+			Total:        "10.00",
+			Items: []Item{
+				{ShortDescription: "a", Price: "1.00"},
+				{ShortDescription: "b", Price: "1.00"},
+			},
+		},
+	}
+
+	_, breakdown, err := rules.Score(re)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, breakdown["item-pair"])
+}
+
+func TestLoadRuleConfigUnsupportedExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.toml")
+	assert.NoError(t, os.WriteFile(path, []byte("rules = {}"), 0o644))
+
+	_, err := LoadRuleConfig(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+//go:build !windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+	"strings"
+)
+
+// namespacedRule wraps a plugin-provided Rule so its Name is prefixed with
+// the plugin it came from. This keeps two plugins (or a plugin and a
+// built-in) from colliding if they happen to pick the same rule name.
+type namespacedRule struct {
+	prefix string
+	inner  Rule
+}
+
+func (n namespacedRule) Name() string { return n.prefix + "." + n.inner.Name() }
+
+func (n namespacedRule) Score(re StorageReceipt) (int, error) { return n.inner.Score(re) }
+
+// LoadPlugins opens every .so file in dir and registers the Rules it exports
+// into rr, namespaced by the plugin's filename (without extension). Each
+// plugin must export a `Rules` symbol with the signature `func() []Rule`.
+func LoadPlugins(dir string, rr *RuleRegistry) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("reading rules dir %q: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".so" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		p, err := plugin.Open(path)
+		if err != nil {
+			return fmt.Errorf("opening plugin %q: %w", path, err)
+		}
+
+		sym, err := p.Lookup("Rules")
+		if err != nil {
+			return fmt.Errorf("plugin %q: looking up Rules symbol: %w", path, err)
+		}
+
+		rulesFn, ok := sym.(func() []Rule)
+		if !ok {
+			return fmt.Errorf("plugin %q: Rules symbol has unexpected type %T, want func() []Rule", path, sym)
+		}
+
+		prefix := strings.TrimSuffix(entry.Name(), ".so")
+		for _, r := range rulesFn() {
+			rr.Register(namespacedRule{prefix: prefix, inner: r})
+		}
+	}
+
+	return nil
+}
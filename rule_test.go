@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+// TestRuleRegistryVersionReflectsParameters confirms that Version() is a
+// fingerprint of the registered rules' actual field values, not just a count
+// of Register calls: two registries built from configs that only retune an
+// existing rule's parameters (not enable/disable it) must report different
+// versions, or a cached score would keep being served after an operator
+// retunes a rule and restarts.
+func TestRuleRegistryVersionReflectsParameters(t *testing.T) {
+	low := NewDefaultRuleRegistry(&RuleConfig{
+		Rules: map[string]RuleParams{
+			"round-dollar-total": {Bonus: intPtr(50)},
+		},
+	})
+	high := NewDefaultRuleRegistry(&RuleConfig{
+		Rules: map[string]RuleParams{
+			"round-dollar-total": {Bonus: intPtr(999)},
+		},
+	})
+
+	if low.Version() == high.Version() {
+		t.Fatalf("Version() did not change when round-dollar-total.Bonus changed from 50 to 999")
+	}
+}
+
+// TestRuleRegistryVersionStableForEqualConfig confirms Version() is
+// deterministic: rebuilding the same config twice must not invalidate
+// previously cached scores.
+func TestRuleRegistryVersionStableForEqualConfig(t *testing.T) {
+	cfg := &RuleConfig{
+		Rules: map[string]RuleParams{
+			"round-dollar-total": {Bonus: intPtr(50)},
+		},
+	}
+
+	a := NewDefaultRuleRegistry(cfg)
+	b := NewDefaultRuleRegistry(cfg)
+
+	if a.Version() != b.Version() {
+		t.Fatalf("Version() differed for two registries built from the same config")
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The built-in rules below are the seven scoring rules from the original
+// prompt, each pulled out into its own Rule implementation so they can be
+// enabled, disabled, or reparameterized via RuleConfig without touching the
+// scoring logic itself.
+
+// RetailerAlphanumericRule awards one point (scaled by Multiplier) for every
+// alphanumeric character in the retailer name.
+type RetailerAlphanumericRule struct {
+	Multiplier float64
+}
+
+func (r RetailerAlphanumericRule) Name() string { return "retailer-alphanumeric" }
+
+func (r RetailerAlphanumericRule) Score(re StorageReceipt) (int, error) {
+	count := 0
+	for _, c := range re.Retailer {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			count++
+		}
+	}
+	return int(math.Round(float64(count) * r.Multiplier)), nil
+}
+
+// RoundDollarRule awards Bonus points if the total is a round dollar amount
+// with no cents.
+type RoundDollarRule struct {
+	Bonus int
+}
+
+func (r RoundDollarRule) Name() string { return "round-dollar-total" }
+
+func (r RoundDollarRule) Score(re StorageReceipt) (int, error) {
+	totalPrice, err := strconv.ParseFloat(re.Total, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if totalPrice == math.Floor(totalPrice) {
+		return r.Bonus, nil
+	}
+	return 0, nil
+}
+
+// QuarterMultipleRule awards Bonus points if the total is a multiple of
+// 0.25.
+type QuarterMultipleRule struct {
+	Bonus int
+}
+
+func (r QuarterMultipleRule) Name() string { return "quarter-multiple-total" }
+
+func (r QuarterMultipleRule) Score(re StorageReceipt) (int, error) {
+	totalPrice, err := strconv.ParseFloat(re.Total, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if math.Mod(totalPrice, 0.25) == 0 {
+		return r.Bonus, nil
+	}
+	return 0, nil
+}
+
+// ItemPairRule awards PointsPerPair points for every two items on the
+// receipt.
+type ItemPairRule struct {
+	PointsPerPair int
+}
+
+func (r ItemPairRule) Name() string { return "item-pair" }
+
+func (r ItemPairRule) Score(re StorageReceipt) (int, error) {
+	pairs := math.Floor(float64(len(re.Items)) / 2)
+	return int(pairs) * r.PointsPerPair, nil
+}
+
+// ItemDescriptionRule awards points for each item whose trimmed description
+// length is a multiple of 3: the item's price is multiplied by Multiplier
+// and rounded up to the nearest integer.
+type ItemDescriptionRule struct {
+	Multiplier float64
+}
+
+func (r ItemDescriptionRule) Name() string { return "item-description-multiple-of-three" }
+
+func (r ItemDescriptionRule) Score(re StorageReceipt) (int, error) {
+	total := 0
+	for _, item := range re.Items {
+		trimmedDesc := strings.TrimSpace(item.ShortDescription)
+		if len(trimmedDesc)%3 != 0 {
+			continue
+		}
+
+		itemPrice, err := strconv.ParseFloat(item.Price, 64)
+		if err != nil {
+			return 0, err
+		}
+
+		total += int(math.Ceil(itemPrice * r.Multiplier))
+	}
+	return total, nil
+}
+
+// OddPurchaseDayRule awards Bonus points if the day in the purchase date is
+// odd.
+type OddPurchaseDayRule struct {
+	Bonus int
+}
+
+func (r OddPurchaseDayRule) Name() string { return "odd-purchase-day" }
+
+func (r OddPurchaseDayRule) Score(re StorageReceipt) (int, error) {
+	splitStrs := strings.Split(re.PurchaseDate, "-")
+	if len(splitStrs) != 3 {
+		return 0, fmt.Errorf("invalid date. Must follow the YYYY-MM-DD scheme")
+	}
+
+	dateNum, err := strconv.Atoi(splitStrs[2])
+	if err != nil {
+		return 0, err
+	}
+
+	if dateNum%2 == 1 {
+		return r.Bonus, nil
+	}
+	return 0, nil
+}
+
+// AfternoonPurchaseRule awards Bonus points if the time of purchase is
+// strictly after StartHour:00 and before EndHour:00.
+type AfternoonPurchaseRule struct {
+	StartHour int
+	EndHour   int
+	Bonus     int
+}
+
+func (r AfternoonPurchaseRule) Name() string { return "afternoon-purchase" }
+
+func (r AfternoonPurchaseRule) Score(re StorageReceipt) (int, error) {
+	timeSplit := strings.Split(re.PurchaseTime, ":")
+	if len(timeSplit) != 2 {
+		return 0, fmt.Errorf("invalid time. Must follow the HH:MM scheme")
+	}
+
+	hrs, err := strconv.Atoi(timeSplit[0])
+	if err != nil {
+		return 0, err
+	}
+	mins, err := strconv.Atoi(timeSplit[1])
+	if err != nil {
+		return 0, err
+	}
+
+	if hrs == r.StartHour && mins == 0 {
+		return 0, nil
+	}
+	if hrs >= r.StartHour && hrs < r.EndHour {
+		return r.Bonus, nil
+	}
+	return 0, nil
+}
+
+// NewDefaultRuleRegistry builds the RuleRegistry containing the seven
+// built-in rules, applying any overrides from cfg. A nil cfg yields the
+// original hardcoded behavior.
+func NewDefaultRuleRegistry(cfg *RuleConfig) *RuleRegistry {
+	rr := NewRuleRegistry()
+
+	builtins := []Rule{
+		RetailerAlphanumericRule{Multiplier: 1},
+		RoundDollarRule{Bonus: 50},
+		QuarterMultipleRule{Bonus: 25},
+		ItemPairRule{PointsPerPair: 5},
+		ItemDescriptionRule{Multiplier: 0.2},
+		OddPurchaseDayRule{Bonus: 6},
+		AfternoonPurchaseRule{StartHour: 14, EndHour: 16, Bonus: 10},
+	}
+
+	for _, r := range builtins {
+		if cfg != nil && cfg.isDisabled(r.Name()) {
+			continue
+		}
+		rr.Register(cfg.apply(r))
+	}
+
+	return rr
+}
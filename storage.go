@@ -0,0 +1,20 @@
+package main
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Storage persists receipts and allows looking them up by id. All three
+// built-in implementations (MemoryStorage, BoltStorage, SQLiteStorage) key
+// by the receipt's UUID so Get is a direct lookup rather than a linear scan.
+// Every method takes a context so callers can cancel or time out a request;
+// implementations should abort long-running scans (List in particular) as
+// soon as the context is done. Implementations must be safe for concurrent
+// use.
+type Storage interface {
+	Put(context.Context, StorageReceipt) error
+	Get(context.Context, uuid.UUID) (StorageReceipt, bool, error)
+	List(context.Context) ([]StorageReceipt, error)
+}
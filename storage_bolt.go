@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	bolt "go.etcd.io/bbolt"
+)
+
+var receiptsBucket = []byte("receipts")
+
+// BoltStorage is a file-backed Storage implementation using bbolt. Keys are
+// the receipt's raw UUID bytes so Get is a direct bucket lookup rather than
+// a scan.
+type BoltStorage struct {
+	db *bolt.DB
+}
+
+// boltReceipt is the on-disk shape of a StorageReceipt. It can't reuse
+// StorageReceipt's own json tags directly: those are tuned for the HTTP
+// response (Points/Breakdown/RulesVersion are `json:"-"` so they never leak
+// to clients), but bolt needs those same fields persisted so the points
+// cache survives a restart.
+type boltReceipt struct {
+	PostReceiptJSON
+	Id           uuid.UUID      `json:"id"`
+	Points       *int           `json:"points,omitempty"`
+	Breakdown    map[string]int `json:"breakdown,omitempty"`
+	RulesVersion string         `json:"rulesVersion,omitempty"`
+}
+
+func toBoltReceipt(re StorageReceipt) boltReceipt {
+	return boltReceipt{
+		PostReceiptJSON: re.PostReceiptJSON,
+		Id:              re.Id,
+		Points:          re.Points,
+		Breakdown:       re.Breakdown,
+		RulesVersion:    re.RulesVersion,
+	}
+}
+
+func (br boltReceipt) toStorageReceipt() StorageReceipt {
+	return StorageReceipt{
+		PostReceiptJSON: br.PostReceiptJSON,
+		Id:              br.Id,
+		Points:          br.Points,
+		Breakdown:       br.Breakdown,
+		RulesVersion:    br.RulesVersion,
+	}
+}
+
+// NewBoltStorage opens (creating if necessary) a bbolt database file at path
+// and ensures the receipts bucket exists.
+func NewBoltStorage(path string) (*BoltStorage, error) {
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("opening bolt db %q: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(receiptsBucket)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating receipts bucket: %w", err)
+	}
+
+	return &BoltStorage{db: db}, nil
+}
+
+func (b *BoltStorage) Put(ctx context.Context, re StorageReceipt) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(toBoltReceipt(re))
+	if err != nil {
+		return fmt.Errorf("marshaling receipt: %w", err)
+	}
+
+	key, err := re.Id.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("marshaling receipt id: %w", err)
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(receiptsBucket).Put(key, data)
+	})
+}
+
+func (b *BoltStorage) Get(ctx context.Context, id uuid.UUID) (StorageReceipt, bool, error) {
+	var br boltReceipt
+	found := false
+
+	if err := ctx.Err(); err != nil {
+		return StorageReceipt{}, false, err
+	}
+
+	key, err := id.MarshalBinary()
+	if err != nil {
+		return StorageReceipt{}, false, fmt.Errorf("marshaling receipt id: %w", err)
+	}
+
+	err = b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(receiptsBucket).Get(key)
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &br)
+	})
+	if err != nil {
+		return StorageReceipt{}, false, fmt.Errorf("reading receipt: %w", err)
+	}
+
+	return br.toStorageReceipt(), found, nil
+}
+
+func (b *BoltStorage) List(ctx context.Context) ([]StorageReceipt, error) {
+	var out []StorageReceipt
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(receiptsBucket).ForEach(func(_, data []byte) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var br boltReceipt
+			if err := json.Unmarshal(data, &br); err != nil {
+				return err
+			}
+			out = append(out, br.toStorageReceipt())
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing receipts: %w", err)
+	}
+
+	return out, nil
+}
+
+// Close releases the underlying bbolt file handle.
+func (b *BoltStorage) Close() error {
+	return b.db.Close()
+}
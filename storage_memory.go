@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// MemoryStorage is an in-memory Storage backed by a map keyed by UUID, so
+// Get is O(1) instead of the linear scan the original []StorageReceipt did.
+// It does not survive a process restart.
+type MemoryStorage struct {
+	mu       sync.RWMutex
+	receipts map[uuid.UUID]StorageReceipt
+}
+
+// NewMemoryStorage returns an empty MemoryStorage.
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		receipts: make(map[uuid.UUID]StorageReceipt),
+	}
+}
+
+func (m *MemoryStorage) Put(ctx context.Context, re StorageReceipt) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.receipts[re.Id] = re
+	return nil
+}
+
+func (m *MemoryStorage) Get(ctx context.Context, id uuid.UUID) (StorageReceipt, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return StorageReceipt{}, false, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	re, ok := m.receipts[id]
+	return re, ok, nil
+}
+
+func (m *MemoryStorage) List(ctx context.Context) ([]StorageReceipt, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make([]StorageReceipt, 0, len(m.receipts))
+	for _, re := range m.receipts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		out = append(out, re)
+	}
+	return out, nil
+}
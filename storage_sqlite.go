@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteMigrations are applied in order, tracked in schema_migrations, so
+// running the server repeatedly against the same file only applies new
+// migrations.
+var sqliteMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS receipts (
+		id            TEXT PRIMARY KEY,
+		retailer      TEXT,
+		purchase_date TEXT,
+		purchase_time TEXT,
+		total         TEXT,
+		items         TEXT,
+		points        INTEGER,
+		breakdown     TEXT,
+		rules_version TEXT
+	)`,
+	// id is already indexed via the PRIMARY KEY constraint above; this is
+	// kept explicit so it survives if the table is ever recreated without one.
+	`CREATE INDEX IF NOT EXISTS idx_receipts_id ON receipts(id)`,
+}
+
+// SQLiteStorage is a Storage implementation backed by SQLite via
+// database/sql.
+type SQLiteStorage struct {
+	db *sql.DB
+}
+
+// NewSQLiteStorage opens (creating if necessary) a SQLite database at path
+// and applies any pending schema migrations.
+func NewSQLiteStorage(path string) (*SQLiteStorage, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening sqlite db %q: %w", path, err)
+	}
+
+	if err := migrateSQLite(db); err != nil {
+		return nil, fmt.Errorf("migrating sqlite db %q: %w", path, err)
+	}
+
+	return &SQLiteStorage{db: db}, nil
+}
+
+func migrateSQLite(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`); err != nil {
+		return err
+	}
+
+	for i, migration := range sqliteMigrations {
+		version := i + 1
+
+		var applied bool
+		row := db.QueryRow(`SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE version = ?)`, version)
+		if err := row.Scan(&applied); err != nil {
+			return err
+		}
+		if applied {
+			continue
+		}
+
+		if _, err := db.Exec(migration); err != nil {
+			return fmt.Errorf("applying migration %d: %w", version, err)
+		}
+		if _, err := db.Exec(`INSERT INTO schema_migrations (version) VALUES (?)`, version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *SQLiteStorage) Put(ctx context.Context, re StorageReceipt) error {
+	itemsJSON, err := json.Marshal(re.Items)
+	if err != nil {
+		return fmt.Errorf("marshaling items: %w", err)
+	}
+
+	var breakdownJSON sql.NullString
+	if re.Breakdown != nil {
+		b, err := json.Marshal(re.Breakdown)
+		if err != nil {
+			return fmt.Errorf("marshaling breakdown: %w", err)
+		}
+		breakdownJSON = sql.NullString{String: string(b), Valid: true}
+	}
+
+	var points sql.NullInt64
+	if re.Points != nil {
+		points = sql.NullInt64{Int64: int64(*re.Points), Valid: true}
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO receipts (id, retailer, purchase_date, purchase_time, total, items, points, breakdown, rules_version)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(id) DO UPDATE SET
+			 retailer      = excluded.retailer,
+			 purchase_date = excluded.purchase_date,
+			 purchase_time = excluded.purchase_time,
+			 total         = excluded.total,
+			 items         = excluded.items,
+			 points        = excluded.points,
+			 breakdown     = excluded.breakdown,
+			 rules_version = excluded.rules_version`,
+		re.Id.String(), re.Retailer, re.PurchaseDate, re.PurchaseTime, re.Total, string(itemsJSON), points, breakdownJSON, re.RulesVersion,
+	)
+	if err != nil {
+		return fmt.Errorf("upserting receipt: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SQLiteStorage) Get(ctx context.Context, id uuid.UUID) (StorageReceipt, bool, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT id, retailer, purchase_date, purchase_time, total, items, points, breakdown, rules_version
+		 FROM receipts WHERE id = ?`,
+		id.String(),
+	)
+
+	re, err := scanReceipt(row)
+	if err == sql.ErrNoRows {
+		return StorageReceipt{}, false, nil
+	}
+	if err != nil {
+		return StorageReceipt{}, false, fmt.Errorf("reading receipt: %w", err)
+	}
+
+	return re, true, nil
+}
+
+func (s *SQLiteStorage) List(ctx context.Context) ([]StorageReceipt, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, retailer, purchase_date, purchase_time, total, items, points, breakdown, rules_version
+		 FROM receipts`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing receipts: %w", err)
+	}
+	defer rows.Close()
+
+	var out []StorageReceipt
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		re, err := scanReceipt(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning receipt: %w", err)
+		}
+		out = append(out, re)
+	}
+
+	return out, rows.Err()
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanReceipt(row rowScanner) (StorageReceipt, error) {
+	var (
+		re            StorageReceipt
+		idStr         string
+		itemsJSON     string
+		points        sql.NullInt64
+		breakdownJSON sql.NullString
+	)
+
+	err := row.Scan(&idStr, &re.Retailer, &re.PurchaseDate, &re.PurchaseTime, &re.Total, &itemsJSON, &points, &breakdownJSON, &re.RulesVersion)
+	if err != nil {
+		return StorageReceipt{}, err
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return StorageReceipt{}, fmt.Errorf("parsing stored id %q: %w", idStr, err)
+	}
+	re.Id = id
+
+	if err := json.Unmarshal([]byte(itemsJSON), &re.Items); err != nil {
+		return StorageReceipt{}, fmt.Errorf("unmarshaling items: %w", err)
+	}
+
+	if points.Valid {
+		p := int(points.Int64)
+		re.Points = &p
+	}
+
+	if breakdownJSON.Valid {
+		if err := json.Unmarshal([]byte(breakdownJSON.String), &re.Breakdown); err != nil {
+			return StorageReceipt{}, fmt.Errorf("unmarshaling breakdown: %w", err)
+		}
+	}
+
+	return re, nil
+}
+
+// Close releases the underlying database handle.
+func (s *SQLiteStorage) Close() error {
+	return s.db.Close()
+}
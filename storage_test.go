@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestStorages returns one instance of every Storage implementation,
+// keyed by backend name, each backed by a fresh t.TempDir() where relevant.
+func newTestStorages(t *testing.T) map[string]Storage {
+	t.Helper()
+
+	boltStorage, err := NewBoltStorage(filepath.Join(t.TempDir(), "receipts.bolt"))
+	assert.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, boltStorage.Close()) })
+
+	sqliteStorage, err := NewSQLiteStorage(filepath.Join(t.TempDir(), "receipts.sqlite"))
+	assert.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, sqliteStorage.Close()) })
+
+	return map[string]Storage{
+		"memory": NewMemoryStorage(),
+		"bolt":   boltStorage,
+		"sqlite": sqliteStorage,
+	}
+}
+
+// TestStorageRoundTrip exercises Put/Get/List against every Storage
+// implementation, in particular confirming that the cached Points,
+// Breakdown, and RulesVersion fields survive a round trip even though
+// they're tagged json:"-" for the HTTP response.
+func TestStorageRoundTrip(t *testing.T) {
+	for name, storage := range newTestStorages(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			id, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			points := 28
+			re := StorageReceipt{
+				Id: id,
+				PostReceiptJSON: PostReceiptJSON{
+					Retailer:     "Target",
+					PurchaseDate: "2022-01-01",
+					PurchaseTime: "13:01",
+					Total:        "35.35",
+				},
+				Points:       &points,
+				Breakdown:    map[string]int{"retailer-alphanumeric": 6, "odd-purchase-day": 6},
+				RulesVersion: "1",
+			}
+
+			assert.NoError(t, storage.Put(ctx, re))
+
+			got, ok, err := storage.Get(ctx, id)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, re.Retailer, got.Retailer)
+			assert.Equal(t, re.Total, got.Total)
+			if assert.NotNil(t, got.Points, "cached points did not survive the round trip") {
+				assert.Equal(t, points, *got.Points)
+			}
+			assert.Equal(t, re.Breakdown, got.Breakdown)
+			assert.Equal(t, re.RulesVersion, got.RulesVersion)
+
+			list, err := storage.List(ctx)
+			assert.NoError(t, err)
+			assert.Len(t, list, 1)
+
+			missing, err := uuid.NewV7()
+			assert.NoError(t, err)
+			_, ok, err = storage.Get(ctx, missing)
+			assert.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
+
+// TestStorageCacheUpdates confirms that re-Put-ing a receipt with an updated
+// Points/Breakdown/RulesVersion (what handleGetReceiptPoints does after a
+// cache miss) is visible on the next Get, against every implementation.
+func TestStorageCacheUpdates(t *testing.T) {
+	for name, storage := range newTestStorages(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			id, err := uuid.NewV7()
+			assert.NoError(t, err)
+
+			stalePoints := 10
+			re := StorageReceipt{
+				Id:           id,
+				Points:       &stalePoints,
+				Breakdown:    map[string]int{"rule-a": 10},
+				RulesVersion: "1",
+			}
+			assert.NoError(t, storage.Put(ctx, re))
+
+			got, ok, err := storage.Get(ctx, id)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, "1", got.RulesVersion)
+
+			// A rule config change bumps RuleRegistry.Version(); the cached
+			// score for the old version is stale, so the receipt is
+			// rescored and re-Put with the new version, as
+			// handleGetReceiptPoints does on a cache miss.
+			freshPoints := 25
+			got.Points = &freshPoints
+			got.Breakdown = map[string]int{"rule-a": 25}
+			got.RulesVersion = "2"
+			assert.NoError(t, storage.Put(ctx, got))
+
+			updated, ok, err := storage.Get(ctx, id)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, "2", updated.RulesVersion)
+			if assert.NotNil(t, updated.Points) {
+				assert.Equal(t, freshPoints, *updated.Points)
+			}
+			assert.Equal(t, map[string]int{"rule-a": 25}, updated.Breakdown)
+		})
+	}
+}